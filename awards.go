@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// AwardsResponse is the response body for GET /awards.
+type AwardsResponse struct {
+	Awards []AwardEvent `json:"awards"`
+}
+
+// ListAwards handles GET /awards?from=&to=&rule=. from and to are Unix
+// timestamps (seconds); either may be omitted to leave that bound open.
+func (s *Server) ListAwards(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var from, to int64
+	if v := q.Get("from"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid from", http.StatusBadRequest)
+			return
+		}
+		from = parsed
+	}
+	if v := q.Get("to"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid to", http.StatusBadRequest)
+			return
+		}
+		to = parsed
+	}
+
+	events, err := s.ledger.Query(from, to, q.Get("rule"))
+	if err != nil {
+		http.Error(w, "Failed to query award ledger", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AwardsResponse{Awards: events})
+}
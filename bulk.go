@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// BulkReceiptCommand is the request body for the bulk receipt endpoints.
+type BulkReceiptCommand struct {
+	ReceiptIds []string `json:"receiptIds"`
+	Comment    string   `json:"comment"`
+}
+
+// BulkResult reports the outcome of a bulk operation for a single receipt.
+type BulkResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	Points  *int   `json:"points,omitempty"`
+}
+
+// BulkResponse is the response body for the bulk receipt endpoints.
+type BulkResponse struct {
+	Results []BulkResult `json:"results"`
+}
+
+// BulkRecalculate handles POST /receipts/bulk/recalculate. It reports the
+// award-ledger point total already recorded for every listed receipt,
+// without touching any other receipt in the store.
+func (s *Server) BulkRecalculate(w http.ResponseWriter, r *http.Request) {
+	var cmd BulkReceiptCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	results := make([]BulkResult, 0, len(cmd.ReceiptIds))
+	for _, id := range cmd.ReceiptIds {
+		if _, err := s.store.Get(id); err != nil {
+			results = append(results, BulkResult{ID: id, Success: false, Error: err.Error()})
+			continue
+		}
+		// Recalculate must not re-append to the award ledger, or it would
+		// look like the receipt earned its points again, so it reports the
+		// points actually recorded for the receipt, the same total
+		// GetPoints and ListReceipts report, rather than re-deriving one.
+		points, err := s.pointsForReceipt(id)
+		if err != nil {
+			results = append(results, BulkResult{ID: id, Success: false, Error: err.Error()})
+			continue
+		}
+		results = append(results, BulkResult{ID: id, Success: true, Points: &points})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BulkResponse{Results: results})
+}
+
+// BulkDelete handles POST /receipts/bulk/delete.
+func (s *Server) BulkDelete(w http.ResponseWriter, r *http.Request) {
+	var cmd BulkReceiptCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	results := make([]BulkResult, 0, len(cmd.ReceiptIds))
+	for _, id := range cmd.ReceiptIds {
+		if err := s.store.Delete(id); err != nil {
+			results = append(results, BulkResult{ID: id, Success: false, Error: err.Error()})
+			continue
+		}
+		results = append(results, BulkResult{ID: id, Success: true})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BulkResponse{Results: results})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ReceiptFilter narrows down the receipts a ReceiptPagedRequestCommand
+// returns.
+type ReceiptFilter struct {
+	Retailer  string
+	DateFrom  *time.Time
+	DateTo    *time.Time
+	TotalMin  *float64
+	TotalMax  *float64
+	MinPoints *int
+}
+
+// ReceiptPagedRequestCommand is the command object for GET /receipts: it
+// self-loads its fields from the incoming request's query string.
+type ReceiptPagedRequestCommand struct {
+	Page          int
+	PageSize      int
+	OrderBy       string
+	SortDirection string
+	Filter        ReceiptFilter
+}
+
+var validOrderBy = map[string]bool{
+	"retailer": true,
+	"total":    true,
+	"points":   true,
+}
+
+// LoadDataFromRequest populates the command from r's query parameters,
+// applying defaults for anything omitted. On invalid input it writes a 400
+// to w and returns the error; callers should stop processing when an error
+// is returned.
+func (c *ReceiptPagedRequestCommand) LoadDataFromRequest(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+
+	c.Page = 1
+	if v := q.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			http.Error(w, "invalid page", http.StatusBadRequest)
+			return fmt.Errorf("invalid page %q", v)
+		}
+		c.Page = page
+	}
+
+	c.PageSize = 20
+	if v := q.Get("pageSize"); v != "" {
+		pageSize, err := strconv.Atoi(v)
+		if err != nil || pageSize < 1 {
+			http.Error(w, "invalid pageSize", http.StatusBadRequest)
+			return fmt.Errorf("invalid pageSize %q", v)
+		}
+		c.PageSize = pageSize
+	}
+
+	c.OrderBy = "retailer"
+	if v := q.Get("orderBy"); v != "" {
+		if !validOrderBy[v] {
+			http.Error(w, "invalid orderBy", http.StatusBadRequest)
+			return fmt.Errorf("invalid orderBy %q", v)
+		}
+		c.OrderBy = v
+	}
+
+	c.SortDirection = "asc"
+	if v := q.Get("sortDirection"); v != "" {
+		if v != "asc" && v != "desc" {
+			http.Error(w, "invalid sortDirection", http.StatusBadRequest)
+			return fmt.Errorf("invalid sortDirection %q", v)
+		}
+		c.SortDirection = v
+	}
+
+	c.Filter.Retailer = q.Get("retailer")
+
+	if v := q.Get("dateFrom"); v != "" {
+		d, err := parsePurchaseDate(v)
+		if err != nil {
+			http.Error(w, "invalid dateFrom", http.StatusBadRequest)
+			return fmt.Errorf("invalid dateFrom %q: %w", v, err)
+		}
+		c.Filter.DateFrom = &d
+	}
+	if v := q.Get("dateTo"); v != "" {
+		d, err := parsePurchaseDate(v)
+		if err != nil {
+			http.Error(w, "invalid dateTo", http.StatusBadRequest)
+			return fmt.Errorf("invalid dateTo %q: %w", v, err)
+		}
+		c.Filter.DateTo = &d
+	}
+
+	if v := q.Get("totalMin"); v != "" {
+		min, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "invalid totalMin", http.StatusBadRequest)
+			return fmt.Errorf("invalid totalMin %q", v)
+		}
+		c.Filter.TotalMin = &min
+	}
+	if v := q.Get("totalMax"); v != "" {
+		max, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "invalid totalMax", http.StatusBadRequest)
+			return fmt.Errorf("invalid totalMax %q", v)
+		}
+		c.Filter.TotalMax = &max
+	}
+
+	if v := q.Get("minPoints"); v != "" {
+		minPoints, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "invalid minPoints", http.StatusBadRequest)
+			return fmt.Errorf("invalid minPoints %q", v)
+		}
+		c.Filter.MinPoints = &minPoints
+	}
+
+	return nil
+}
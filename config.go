@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config is the top-level application configuration, loaded from a TOML
+// file (see config.toml.example).
+type Config struct {
+	Storage StorageConfig `toml:"storage"`
+}
+
+// StorageConfig selects and tunes the ReceiptStore driver.
+type StorageConfig struct {
+	// Driver is "memory" (default), "sqlite3", or "postgres".
+	Driver string `toml:"driver"`
+	// DSN is the data source name, only used by SQL drivers.
+	DSN string `toml:"dsn"`
+	// MaxOpenConns and MaxIdleConns tune the SQL connection pool.
+	MaxOpenConns int `toml:"max_open_conns"`
+	MaxIdleConns int `toml:"max_idle_conns"`
+	// ConnMaxLifetime bounds how long a pooled connection may be reused.
+	ConnMaxLifetime time.Duration `toml:"conn_max_lifetime"`
+}
+
+// defaultConfig is used whenever no config file is present, so the server
+// keeps working out of the box with the in-memory store.
+func defaultConfig() Config {
+	return Config{
+		Storage: StorageConfig{
+			Driver:          "memory",
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: time.Hour,
+		},
+	}
+}
+
+// LoadConfig reads and decodes the TOML config file at path. If path does
+// not exist, it returns defaultConfig() rather than an error so the server
+// can run without any configuration at all.
+func LoadConfig(path string) (Config, error) {
+	cfg := defaultConfig()
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return cfg, nil
+	}
+
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
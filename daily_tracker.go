@@ -0,0 +1,66 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// DailyDataTracker accumulates a running total that automatically resets
+// once a full day has elapsed since it was last reset, so a cap like "N
+// points per retailer per day" can be enforced without a separate cron job.
+type DailyDataTracker struct {
+	mu        sync.Mutex
+	total     int
+	lastReset time.Time
+}
+
+// NewDailyDataTracker starts a tracker whose window begins at now.
+func NewDailyDataTracker(now time.Time) *DailyDataTracker {
+	return &DailyDataTracker{lastReset: now}
+}
+
+// Reset zeroes the running total and starts a new window at now.
+func (t *DailyDataTracker) Reset(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total = 0
+	t.lastReset = now
+}
+
+// IsOver24Hours reports whether now is at least 24 hours past the start of
+// the current window.
+func (t *DailyDataTracker) IsOver24Hours(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return now.Sub(t.lastReset) >= 24*time.Hour
+}
+
+// Add rolls the window over if it's stale, adds points to the running
+// total, and returns the new total.
+func (t *DailyDataTracker) Add(now time.Time, points int) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if now.Sub(t.lastReset) >= 24*time.Hour {
+		t.total = 0
+		t.lastReset = now
+	}
+	t.total += points
+	return t.total
+}
+
+// Snapshot returns the tracker's current running total and window start, so
+// callers can persist it alongside the award ledger.
+func (t *DailyDataTracker) Snapshot() (int, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total, t.lastReset
+}
+
+// Restore sets the tracker's running total and window start directly,
+// used to rehydrate a tracker from persisted storage after a restart.
+func (t *DailyDataTracker) Restore(total int, lastReset time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total = total
+	t.lastReset = lastReset
+}
@@ -0,0 +1,21 @@
+package main
+
+import "net/http"
+
+// Healthz handles GET /healthz: a liveness check that only confirms the
+// process is up and serving requests.
+func (s *Server) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// Readyz handles GET /readyz: a readiness check that also confirms the
+// storage backend is reachable.
+func (s *Server) Readyz(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.Ping(); err != nil {
+		http.Error(w, "storage not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ready"))
+}
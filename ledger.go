@@ -0,0 +1,137 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// AwardEvent is one immutable record of points being awarded to a receipt
+// under a single scoring rule.
+type AwardEvent struct {
+	When      int64  `json:"when"`
+	ReceiptID string `json:"receiptId"`
+	Rule      string `json:"rule"`
+	Points    int    `json:"points"`
+}
+
+// AwardStore abstracts how AwardEvents and the per-retailer daily-cap
+// trackers are persisted, so the GET /awards audit trail and daily caps
+// survive a restart the same way receipts do via ReceiptStore.
+type AwardStore interface {
+	RecordAward(event AwardEvent) error
+	// QueryAwards returns every event matching the given filters, sorted by
+	// When. A zero from/to or an empty rule is treated as unbounded.
+	QueryAwards(from, to int64, rule string) ([]AwardEvent, error)
+	// SumPoints returns the total points recorded in the ledger for a
+	// single receipt. It is the source of truth for "how many points did
+	// this receipt earn", since it reflects whatever daily cap applied at
+	// award time instead of re-deriving one against a tracker snapshot.
+	SumPoints(receiptID string) (int, error)
+	// LoadDailyTracker returns the persisted running total and window start
+	// for retailer. found is false if no tracker has been saved yet.
+	LoadDailyTracker(retailer string) (total int, lastReset time.Time, found bool, err error)
+	SaveDailyTracker(retailer string, total int, lastReset time.Time) error
+}
+
+// dailyTrackerState is the persisted snapshot of a DailyDataTracker.
+type dailyTrackerState struct {
+	total     int
+	lastReset time.Time
+}
+
+// MemoryAwardStore is the default AwardStore: events and trackers live only
+// in process memory and do not survive a restart.
+type MemoryAwardStore struct {
+	mu       sync.RWMutex
+	events   []AwardEvent
+	trackers map[string]dailyTrackerState
+}
+
+// NewMemoryAwardStore returns an empty MemoryAwardStore.
+func NewMemoryAwardStore() *MemoryAwardStore {
+	return &MemoryAwardStore{trackers: make(map[string]dailyTrackerState)}
+}
+
+func (s *MemoryAwardStore) RecordAward(event AwardEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *MemoryAwardStore) QueryAwards(from, to int64, rule string) ([]AwardEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]AwardEvent, 0, len(s.events))
+	for _, event := range s.events {
+		if from != 0 && event.When < from {
+			continue
+		}
+		if to != 0 && event.When > to {
+			continue
+		}
+		if rule != "" && event.Rule != rule {
+			continue
+		}
+		matches = append(matches, event)
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].When < matches[j].When })
+	return matches, nil
+}
+
+func (s *MemoryAwardStore) SumPoints(receiptID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	total := 0
+	for _, event := range s.events {
+		if event.ReceiptID == receiptID {
+			total += event.Points
+		}
+	}
+	return total, nil
+}
+
+func (s *MemoryAwardStore) LoadDailyTracker(retailer string) (int, time.Time, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, ok := s.trackers[retailer]
+	return state.total, state.lastReset, ok, nil
+}
+
+func (s *MemoryAwardStore) SaveDailyTracker(retailer string, total int, lastReset time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trackers[retailer] = dailyTrackerState{total: total, lastReset: lastReset}
+	return nil
+}
+
+// AwardLedger is an append-only log of AwardEvents backed by an AwardStore,
+// so rule changes and daily caps can be audited after the fact instead of
+// trusting a single opaque points total.
+type AwardLedger struct {
+	store AwardStore
+}
+
+// NewAwardLedger wraps store in an AwardLedger.
+func NewAwardLedger(store AwardStore) *AwardLedger {
+	return &AwardLedger{store: store}
+}
+
+// Record appends event to the ledger.
+func (l *AwardLedger) Record(event AwardEvent) error {
+	return l.store.RecordAward(event)
+}
+
+// Query returns every event matching the given filters, sorted by When.
+// A zero from/to or an empty rule is treated as unbounded.
+func (l *AwardLedger) Query(from, to int64, rule string) ([]AwardEvent, error) {
+	return l.store.QueryAwards(from, to, rule)
+}
+
+// SumPoints returns the total points recorded in the ledger for receiptID.
+func (l *AwardLedger) SumPoints(receiptID string) (int, error) {
+	return l.store.SumPoints(receiptID)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SQLAwardStore is an AwardStore backed by database/sql, so the award
+// ledger and daily-cap trackers persist across restarts the same way
+// receipts do via SQLStore.
+type SQLAwardStore struct {
+	db     *sql.DB
+	driver string
+}
+
+// NewSQLAwardStore wraps an already-opened *sql.DB and ensures the award
+// ledger tables exist. driver is the same "sqlite3"/"postgres" value used to
+// open db, and picks how query placeholders are rendered.
+func NewSQLAwardStore(db *sql.DB, driver string) (*SQLAwardStore, error) {
+	store := &SQLAwardStore{db: db, driver: driver}
+	if err := store.ensureSchema(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *SQLAwardStore) q(query string) string {
+	return rewritePlaceholders(s.driver, query)
+}
+
+func (s *SQLAwardStore) ensureSchema() error {
+	if _, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS award_events (
+			happened_at BIGINT NOT NULL,
+			receipt_id  TEXT NOT NULL,
+			rule        TEXT NOT NULL,
+			points      INTEGER NOT NULL
+		)
+	`); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS daily_trackers (
+			retailer   TEXT PRIMARY KEY,
+			total      INTEGER NOT NULL,
+			last_reset BIGINT NOT NULL
+		)
+	`)
+	return err
+}
+
+func (s *SQLAwardStore) RecordAward(event AwardEvent) error {
+	_, err := s.db.Exec(s.q(`
+		INSERT INTO award_events (happened_at, receipt_id, rule, points)
+		VALUES (?, ?, ?, ?)
+	`), event.When, event.ReceiptID, event.Rule, event.Points)
+	return err
+}
+
+func (s *SQLAwardStore) QueryAwards(from, to int64, rule string) ([]AwardEvent, error) {
+	query := `SELECT happened_at, receipt_id, rule, points FROM award_events WHERE 1=1`
+	var args []interface{}
+	if from != 0 {
+		query += ` AND happened_at >= ?`
+		args = append(args, from)
+	}
+	if to != 0 {
+		query += ` AND happened_at <= ?`
+		args = append(args, to)
+	}
+	if rule != "" {
+		query += ` AND rule = ?`
+		args = append(args, rule)
+	}
+	query += ` ORDER BY happened_at ASC`
+
+	rows, err := s.db.Query(s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]AwardEvent, 0)
+	for rows.Next() {
+		var event AwardEvent
+		if err := rows.Scan(&event.When, &event.ReceiptID, &event.Rule, &event.Points); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (s *SQLAwardStore) SumPoints(receiptID string) (int, error) {
+	var total sql.NullInt64
+	row := s.db.QueryRow(s.q(`SELECT SUM(points) FROM award_events WHERE receipt_id = ?`), receiptID)
+	if err := row.Scan(&total); err != nil {
+		return 0, err
+	}
+	return int(total.Int64), nil
+}
+
+func (s *SQLAwardStore) LoadDailyTracker(retailer string) (int, time.Time, bool, error) {
+	var total int
+	var lastReset int64
+	row := s.db.QueryRow(s.q(`SELECT total, last_reset FROM daily_trackers WHERE retailer = ?`), retailer)
+	if err := row.Scan(&total, &lastReset); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, time.Time{}, false, nil
+		}
+		return 0, time.Time{}, false, err
+	}
+	return total, time.Unix(lastReset, 0), true, nil
+}
+
+func (s *SQLAwardStore) SaveDailyTracker(retailer string, total int, lastReset time.Time) error {
+	_, err := s.db.Exec(s.q(`
+		INSERT INTO daily_trackers (retailer, total, last_reset)
+		VALUES (?, ?, ?)
+		ON CONFLICT (retailer) DO UPDATE SET
+			total = excluded.total,
+			last_reset = excluded.last_reset
+	`), retailer, total, lastReset.Unix())
+	return err
+}
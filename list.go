@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReceiptSummary is the shape returned for each receipt in a paged list
+// response.
+type ReceiptSummary struct {
+	ID       string `json:"id"`
+	Retailer string `json:"retailer"`
+	Total    string `json:"total"`
+	Points   int    `json:"points"`
+}
+
+// ReceiptPage is the response body for GET /receipts.
+type ReceiptPage struct {
+	Page       int              `json:"page"`
+	PageSize   int              `json:"pageSize"`
+	TotalCount int              `json:"totalCount"`
+	Results    []ReceiptSummary `json:"results"`
+}
+
+// ListReceipts handles GET /receipts
+func (s *Server) ListReceipts(w http.ResponseWriter, r *http.Request) {
+	var cmd ReceiptPagedRequestCommand
+	if err := cmd.LoadDataFromRequest(w, r); err != nil {
+		return
+	}
+
+	receipts, err := s.store.List()
+	if err != nil {
+		http.Error(w, "Failed to load receipts", http.StatusInternalServerError)
+		return
+	}
+
+	summaries := make([]ReceiptSummary, 0, len(receipts))
+	for id, receipt := range receipts {
+		if !matchesFilter(receipt, cmd.Filter) {
+			continue
+		}
+		// Report the same award-ledger point total GetPoints and
+		// BulkRecalculate report, so the list and minPoints filtering agree
+		// with them instead of the uncapped calculatePointsBreakdown total.
+		points, err := s.pointsForReceipt(id)
+		if err != nil {
+			http.Error(w, "Failed to load receipt points", http.StatusInternalServerError)
+			return
+		}
+		summaries = append(summaries, ReceiptSummary{
+			ID:       id,
+			Retailer: receipt.Retailer,
+			Total:    receipt.Total.Raw,
+			Points:   points,
+		})
+	}
+
+	if cmd.Filter.MinPoints != nil {
+		filtered := summaries[:0]
+		for _, summary := range summaries {
+			if summary.Points >= *cmd.Filter.MinPoints {
+				filtered = append(filtered, summary)
+			}
+		}
+		summaries = filtered
+	}
+
+	sortSummaries(summaries, cmd.OrderBy, cmd.SortDirection)
+
+	totalCount := len(summaries)
+	start := (cmd.Page - 1) * cmd.PageSize
+	if start > totalCount {
+		start = totalCount
+	}
+	end := start + cmd.PageSize
+	if end > totalCount {
+		end = totalCount
+	}
+
+	page := ReceiptPage{
+		Page:       cmd.Page,
+		PageSize:   cmd.PageSize,
+		TotalCount: totalCount,
+		Results:    summaries[start:end],
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// matchesFilter reports whether receipt satisfies every filter criterion
+// except MinPoints, which is applied after points are computed.
+func matchesFilter(receipt Receipt, filter ReceiptFilter) bool {
+	if filter.Retailer != "" && !containsFold(receipt.Retailer, filter.Retailer) {
+		return false
+	}
+
+	date := receipt.PurchaseDate.Time
+	if filter.DateFrom != nil && date.Before(*filter.DateFrom) {
+		return false
+	}
+	if filter.DateTo != nil && date.After(*filter.DateTo) {
+		return false
+	}
+
+	total := receipt.Total.Decimal
+	if filter.TotalMin != nil && total.LessThan(decimal.NewFromFloat(*filter.TotalMin)) {
+		return false
+	}
+	if filter.TotalMax != nil && total.GreaterThan(decimal.NewFromFloat(*filter.TotalMax)) {
+		return false
+	}
+
+	return true
+}
+
+// containsFold reports whether haystack contains needle, ignoring case.
+func containsFold(haystack, needle string) bool {
+	return strings.Contains(strings.ToLower(haystack), strings.ToLower(needle))
+}
+
+func sortSummaries(summaries []ReceiptSummary, orderBy, direction string) {
+	less := func(i, j int) bool {
+		switch orderBy {
+		case "total":
+			ti, _ := decimal.NewFromString(summaries[i].Total)
+			tj, _ := decimal.NewFromString(summaries[j].Total)
+			return ti.LessThan(tj)
+		case "points":
+			return summaries[i].Points < summaries[j].Points
+		default: // "retailer"
+			return summaries[i].Retailer < summaries[j].Retailer
+		}
+	}
+	if direction == "desc" {
+		sort.Slice(summaries, func(i, j int) bool { return less(j, i) })
+	} else {
+		sort.Slice(summaries, less)
+	}
+}
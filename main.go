@@ -1,156 +1,349 @@
-package main
-
-import (
-	"fmt"
-	"log"
-	"net/http"
-	"encoding/json"
-	"github.com/gorilla/mux"
-	"github.com/google/uuid"
-	"strconv"
-	"strings"
-	"unicode"
-)
-
-// In-memory storage for receipts
-var receiptStore = make(map[string]Receipt)
-
-// Receipt represents the structure of the receipt.
-type Receipt struct {
-	Retailer     string  `json:"retailer"`
-	PurchaseDate string  `json:"purchaseDate"`
-	PurchaseTime string  `json:"purchaseTime"`
-	Total        string  `json:"total"`
-	Items        []Item  `json:"items"`
-}
-
-// Item represents the structure of items in a receipt.
-type Item struct {
-	ShortDescription string `json:"shortDescription"`
-	Price            string `json:"price"`
-}
-
-// ProcessReceipt handles POST /receipts/process
-func ProcessReceipt(w http.ResponseWriter, r *http.Request) {
-	var receipt Receipt
-	if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
-		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
-		return
-	}
-
-	// Generate a unique receipt ID
-	id := uuid.New().String()
-
-	// Store the receipt data in memory (temporary)
-	receiptStore[id] = receipt
-
-	// Send the response with the receipt ID
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"id": id})
-}
-
-// GetPoints handles GET /receipts/{id}/points
-func GetPoints(w http.ResponseWriter, r *http.Request) {
-	id := mux.Vars(r)["id"]
-
-	// Retrieve the receipt data for the given ID
-	receipt, exists := receiptStore[id]
-	if !exists {
-		http.Error(w, "Receipt not found", http.StatusNotFound)
-		return
-	}
-
-	// Calculate points for the receipt
-	points := calculatePoints(receipt)
-
-	// Send the response with the points
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]int{"points": points})
-}
-
-// calculatePoints calculates the points based on the receipt data
-func calculatePoints(receipt Receipt) int {
-	points := 0
-
-	// 1. One point for every alphanumeric character in the retailer name
-	points += alphaNumericCount(receipt.Retailer)
-
-	// 2. 50 points if the total is a round dollar amount with no cents
-	total, err := strconv.ParseFloat(receipt.Total, 64)
-	if err == nil && total == float64(int(total)) {
-		points += 50
-	}
-
-	// 3. 25 points if the total is a multiple of 0.25
-	if int(total * 100) % 25 == 0 {
-		points += 25
-	}
-
-	// 4. 5 points for every two items on the receipt
-	points += int(len(receipt.Items) / 2) * 5
-
-	// 5. If the trimmed length of the item description is a multiple of 3, multiply the price by 0.2 and round up
-	for _, item := range receipt.Items {
-		itemDescLength := len(strings.TrimSpace(item.ShortDescription))
-		if itemDescLength % 3 == 0 {
-			price, err := strconv.ParseFloat(item.Price, 64)
-			if err == nil {
-				points += int(price * 0.2) + 1 // Round up
-			}
-		}
-	}
-
-	// 6. 6 points if the day in the purchase date is odd
-	day, err := strconv.Atoi(strings.Split(receipt.PurchaseDate, "-")[2])
-	if err == nil && day % 2 == 1 {
-		points += 6
-	}
-
-	// 7. 10 points if the time of purchase is after 2:00pm and before 4:00pm
-	hour, err := parseTime(receipt.PurchaseTime)
-	if err == nil {
-		// Check if the hour is between 14 (2:00 PM) and 16 (4:00 PM)
-		if hour >= 14 && hour < 16 {
-			points += 10
-		}
-	}
-
-	return points
-}
-
-// alphaNumericCount counts the alpha numeric characters retailer name
-func alphaNumericCount(s string) int {
-	count := 0
-	for _, char := range s {
-		if unicode.IsLetter(char) || unicode.IsDigit(char) {
-			count++
-		}
-	}
-	return count
-}
-
-// parseTime parses a time in 24-hour format (HH:MM) and returns the hour
-func parseTime(timeStr string) (int, error) {
-	parts := strings.Split(timeStr, ":")
-	if len(parts) != 2 {
-		// Return error if the time format is invalid
-		return 0, fmt.Errorf("invalid time format")
-	}
-	
-	hour, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return 0, fmt.Errorf("invalid hour")
-	}
-	
-	return hour, nil
-}
-
-func main() {
-	r := mux.NewRouter()
-
-	r.HandleFunc("/receipts/process", ProcessReceipt).Methods("POST")
-	r.HandleFunc("/receipts/{id}/points", GetPoints).Methods("GET")
-
-	log.Println("Server running on :8080")
-	log.Fatal(http.ListenAndServe(":8080", r))
-}
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shopspring/decimal"
+)
+
+// Server holds the shared dependencies for the HTTP handlers.
+type Server struct {
+	store      ReceiptStore
+	rules      RulesConfig
+	ledger     *AwardLedger
+	awardStore AwardStore
+
+	dailyMu       sync.Mutex
+	dailyTrackers map[string]*DailyDataTracker // keyed by retailer; cached view of awardStore
+}
+
+// dailyTracker returns (creating and rehydrating from awardStore if needed)
+// the DailyDataTracker for retailer, used to cap item-description-bonus
+// awards per retailer per day.
+func (s *Server) dailyTracker(retailer string) *DailyDataTracker {
+	s.dailyMu.Lock()
+	defer s.dailyMu.Unlock()
+	tracker, ok := s.dailyTrackers[retailer]
+	if !ok {
+		tracker = NewDailyDataTracker(time.Now())
+		if total, lastReset, found, err := s.awardStore.LoadDailyTracker(retailer); err == nil && found {
+			tracker.Restore(total, lastReset)
+		}
+		s.dailyTrackers[retailer] = tracker
+	}
+	return tracker
+}
+
+// trimToCap reduces points if running, the per-retailer daily total with
+// points already added to it, is past dailyCap (zero means uncapped).
+func trimToCap(dailyCap, running, points int) int {
+	if dailyCap <= 0 {
+		return points
+	}
+	over := running - dailyCap
+	if over <= 0 {
+		return points
+	}
+	if over > points {
+		over = points
+	}
+	return points - over
+}
+
+// recordAwards records each award in the ledger, capping
+// item-description-bonus points per retailer per day, and returns the
+// (possibly capped) total.
+func (s *Server) recordAwards(receiptID string, receipt Receipt, awards []RuleAward) (int, error) {
+	now := time.Now()
+	tracker := s.dailyTracker(receipt.Retailer)
+
+	total := 0
+	for _, award := range awards {
+		points := award.Points
+		if award.Rule == RuleItemDescriptionBonus && s.rules.ItemDescriptionDailyCap > 0 {
+			running := tracker.Add(now, points)
+			points = trimToCap(s.rules.ItemDescriptionDailyCap, running, points)
+		}
+		total += points
+		if err := s.ledger.Record(AwardEvent{
+			When:      now.Unix(),
+			ReceiptID: receiptID,
+			Rule:      award.Rule,
+			Points:    points,
+		}); err != nil {
+			return total, err
+		}
+	}
+
+	trackerTotal, lastReset := tracker.Snapshot()
+	if err := s.awardStore.SaveDailyTracker(receipt.Retailer, trackerTotal, lastReset); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+// pointsForReceipt returns the points actually recorded in the award ledger
+// for receiptID. It is the single source of truth every read-only endpoint
+// (GetPoints, BulkRecalculate, ListReceipts) reports points from, so they
+// agree with each other and with GET /awards instead of re-simulating the
+// daily cap against a tracker snapshot that already includes this
+// receipt's own prior contribution.
+func (s *Server) pointsForReceipt(receiptID string) (int, error) {
+	return s.ledger.SumPoints(receiptID)
+}
+
+// ProcessReceipt handles POST /receipts/process
+func (s *Server) ProcessReceipt(w http.ResponseWriter, r *http.Request) {
+	var receipt Receipt
+	if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
+		var verrs *ValidationErrors
+		if errors.As(err, &verrs) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": verrs.Errors})
+			return
+		}
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	// Generate a unique receipt ID
+	id := uuid.New().String()
+
+	if err := s.store.Save(id, receipt); err != nil {
+		http.Error(w, "Failed to store receipt", http.StatusInternalServerError)
+		return
+	}
+	receiptsProcessedTotal.Inc()
+
+	// Award points exactly once, at creation time, so the ledger holds one
+	// event per rule per receipt instead of growing every time someone
+	// reads /points.
+	_, awards := calculatePointsBreakdown(receipt, s.rules)
+	if _, err := s.recordAwards(id, receipt, awards); err != nil {
+		http.Error(w, "Failed to record award ledger", http.StatusInternalServerError)
+		return
+	}
+
+	// Send the response with the receipt ID
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"id": id})
+}
+
+// GetPoints handles GET /receipts/{id}/points
+func (s *Server) GetPoints(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if _, err := s.store.Get(id); err == ErrReceiptNotFound {
+		http.Error(w, "Receipt not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to load receipt", http.StatusInternalServerError)
+		return
+	}
+
+	// GET is a pure read: it must not mutate the award ledger or the daily
+	// cap trackers, so calling it repeatedly for the same receipt is safe.
+	// It reports the points actually recorded for this receipt at process
+	// time, rather than recomputing (and potentially double-capping) them.
+	points, err := s.pointsForReceipt(id)
+	if err != nil {
+		http.Error(w, "Failed to load award ledger", http.StatusInternalServerError)
+		return
+	}
+	pointsCalculatedSum.Add(float64(points))
+
+	// Send the response with the points
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"points": points})
+}
+
+// RuleAward is the points a single scoring rule contributed to a receipt,
+// before any daily cap is applied.
+type RuleAward struct {
+	Rule   string
+	Points int
+}
+
+// Scoring rule names, used both as AwardEvent.Rule values and as the
+// "rule" query filter on GET /awards.
+const (
+	RuleRetailerAlphaNumeric = "retailer-alphanumeric"
+	RuleRoundDollarBonus     = "round-dollar-bonus"
+	RuleQuarterMultiple      = "quarter-multiple-bonus"
+	RuleItemPair             = "item-pair-points"
+	RuleItemDescriptionBonus = "item-description-bonus"
+	RuleOddDayBonus          = "odd-day-bonus"
+	RulePurchaseWindow       = "purchase-window"
+)
+
+// calculatePointsBreakdown calculates the total points a receipt earns
+// under the configured scoring rules, plus the per-rule awards that made up
+// that total, so callers can record an auditable trail of how the points
+// were earned.
+func calculatePointsBreakdown(receipt Receipt, rules RulesConfig) (int, []RuleAward) {
+	points := 0
+	var awards []RuleAward
+
+	award := func(rule string, value int) {
+		if value == 0 {
+			return
+		}
+		points += value
+		awards = append(awards, RuleAward{Rule: rule, Points: value})
+	}
+
+	// 1. Points for every alphanumeric character in the retailer name
+	award(RuleRetailerAlphaNumeric, alphaNumericCount(receipt.Retailer)*rules.AlphaNumericPoints)
+
+	// 2. Bonus if the total is a round dollar amount with no cents
+	total := receipt.Total.Decimal
+	if total.Equal(total.Truncate(0)) {
+		award(RuleRoundDollarBonus, rules.RoundDollarBonus)
+	}
+
+	// 3. Bonus if the total is a multiple of 0.25
+	if total.Mod(decimal.NewFromFloat(0.25)).IsZero() {
+		award(RuleQuarterMultiple, rules.QuarterMultipleBonus)
+	}
+
+	// 4. Points for every two items on the receipt
+	award(RuleItemPair, int(len(receipt.Items)/2)*rules.ItemPairPoints)
+
+	// 5. If the trimmed length of the item description is a multiple of
+	// ItemDescriptionMultiple, multiply the price by the configured
+	// multiplier and round up
+	multiplier := decimal.NewFromFloat(rules.ItemDescriptionPriceMultiplier)
+	for _, item := range receipt.Items {
+		itemDescLength := len(strings.TrimSpace(item.ShortDescription))
+		if itemDescLength%rules.ItemDescriptionMultiple == 0 {
+			award(RuleItemDescriptionBonus, int(item.Price.Decimal.Mul(multiplier).Ceil().IntPart())) // Round up
+		}
+	}
+
+	// 6. Bonus if the day in the purchase date is odd
+	if receipt.PurchaseDate.Time.Day()%2 == 1 {
+		award(RuleOddDayBonus, rules.OddDayPoints)
+	}
+
+	// 7. Bonus for every configured purchase-time window the receipt falls in
+	purchaseTime := receipt.PurchaseTime.Time
+	for _, window := range rules.PurchaseWindows {
+		start, err := parseTime(window.Start)
+		if err != nil {
+			continue
+		}
+		end, err := parseTime(window.End)
+		if err != nil {
+			continue
+		}
+		if !purchaseTime.Before(start) && purchaseTime.Before(end) {
+			award(RulePurchaseWindow, window.Points)
+		}
+	}
+
+	return points, awards
+}
+
+// alphaNumericCount counts the alpha numeric characters retailer name
+func alphaNumericCount(s string) int {
+	count := 0
+	for _, char := range s {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) {
+			count++
+		}
+	}
+	return count
+}
+
+// newStores builds the ReceiptStore and AwardStore configured in
+// cfg.Storage. Both share the same underlying database connection, so the
+// award ledger and daily-cap trackers persist (or don't) together with
+// receipts.
+func newStores(cfg Config) (ReceiptStore, AwardStore, error) {
+	switch cfg.Storage.Driver {
+	case "", "memory":
+		return NewMemoryStore(), NewMemoryAwardStore(), nil
+	case "sqlite3", "postgres":
+		db, err := sql.Open(cfg.Storage.Driver, cfg.Storage.DSN)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening %s database: %w", cfg.Storage.Driver, err)
+		}
+		db.SetMaxOpenConns(cfg.Storage.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.Storage.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.Storage.ConnMaxLifetime)
+
+		receiptStore, err := NewSQLStore(db, cfg.Storage.Driver)
+		if err != nil {
+			return nil, nil, err
+		}
+		awardStore, err := NewSQLAwardStore(db, cfg.Storage.Driver)
+		if err != nil {
+			return nil, nil, err
+		}
+		return receiptStore, awardStore, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+func main() {
+	cfg, err := LoadConfig("config.toml")
+	if err != nil {
+		log.Fatalf("loading config: %v", err)
+	}
+
+	store, awardStore, err := newStores(cfg)
+	if err != nil {
+		log.Fatalf("initializing storage: %v", err)
+	}
+
+	rules, err := LoadRules("rules.toml")
+	if err != nil {
+		log.Fatalf("loading scoring rules: %v", err)
+	}
+
+	server := &Server{
+		store:         store,
+		rules:         rules,
+		ledger:        NewAwardLedger(awardStore),
+		awardStore:    awardStore,
+		dailyTrackers: make(map[string]*DailyDataTracker),
+	}
+
+	r := mux.NewRouter()
+	r.Use(LoggingMiddleware)
+
+	r.HandleFunc("/receipts/process", server.ProcessReceipt).Methods("POST")
+	r.HandleFunc("/receipts/{id}/points", server.GetPoints).Methods("GET")
+	r.HandleFunc("/receipts", server.ListReceipts).Methods("GET")
+	r.HandleFunc("/receipts/bulk/recalculate", server.BulkRecalculate).Methods("POST")
+	r.HandleFunc("/receipts/bulk/delete", server.BulkDelete).Methods("POST")
+	r.HandleFunc("/awards", server.ListAwards).Methods("GET")
+	r.HandleFunc("/healthz", server.Healthz).Methods("GET")
+	r.HandleFunc("/readyz", server.Readyz).Methods("GET")
+	r.Handle("/metrics", promhttp.Handler()).Methods("GET")
+
+	httpServer := &http.Server{
+		Addr:         ":8080",
+		Handler:      r,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	log.Println("Server running on :8080")
+	log.Fatal(httpServer.ListenAndServe())
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics exposed on /metrics.
+var (
+	receiptsProcessedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "receipts_processed_total",
+		Help: "Total number of receipts accepted by POST /receipts/process.",
+	})
+
+	pointsCalculatedSum = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "points_calculated_sum",
+		Help: "Running sum of points returned by GET /receipts/{id}/points.",
+	})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "code"})
+)
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// statusRecorder wraps a ResponseWriter to capture the status code written,
+// since http.ResponseWriter has no getter for it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// LoggingMiddleware emits a structured log line and records Prometheus
+// metrics for every request it handles.
+func LoggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		requestID := uuid.New().String()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		duration := time.Since(start)
+		route := routeTemplate(r)
+
+		httpRequestDuration.
+			WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).
+			Observe(duration.Seconds())
+
+		slog.Info("http_request",
+			"request_id", requestID,
+			"method", r.Method,
+			"route", route,
+			"status", rec.status,
+			"duration_ms", duration.Milliseconds(),
+		)
+	})
+}
+
+// routeTemplate returns the mux route pattern (e.g. "/receipts/{id}/points")
+// rather than the literal path, so metrics and logs don't fan out per id.
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unknown"
+	}
+	tpl, err := route.GetPathTemplate()
+	if err != nil {
+		return "unknown"
+	}
+	return tpl
+}
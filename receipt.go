@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Receipt represents the structure of the receipt. Total, PurchaseDate and
+// PurchaseTime are typed, but marshal back to the exact string the client
+// sent so external consumers see no schema change.
+type Receipt struct {
+	Retailer     string      `json:"retailer"`
+	PurchaseDate DateString  `json:"purchaseDate"`
+	PurchaseTime TimeString  `json:"purchaseTime"`
+	Total        MoneyAmount `json:"total"`
+	Items        []Item      `json:"items"`
+}
+
+// Item represents the structure of items in a receipt.
+type Item struct {
+	ShortDescription string      `json:"shortDescription"`
+	Price            MoneyAmount `json:"price"`
+}
+
+// ValidationError describes one malformed field on an incoming request.
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors aggregates every ValidationError found while decoding a
+// Receipt, so the caller can report them all at once instead of stopping at
+// the first bad field.
+type ValidationErrors struct {
+	Errors []ValidationError
+}
+
+func (e *ValidationErrors) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, ve := range e.Errors {
+		parts[i] = fmt.Sprintf("%s: %s", ve.Field, ve.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// UnmarshalJSON decodes a Receipt field-by-field so that every malformed
+// field is reported, rather than failing on the first one.
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Retailer     string            `json:"retailer"`
+		PurchaseDate json.RawMessage   `json:"purchaseDate"`
+		PurchaseTime json.RawMessage   `json:"purchaseTime"`
+		Total        json.RawMessage   `json:"total"`
+		Items        []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return &ValidationErrors{Errors: []ValidationError{{Field: "", Message: err.Error()}}}
+	}
+
+	var errs []ValidationError
+
+	r.Retailer = raw.Retailer
+	if strings.TrimSpace(raw.Retailer) == "" {
+		errs = append(errs, ValidationError{Field: "retailer", Message: "must not be empty"})
+	}
+
+	if err := json.Unmarshal(raw.PurchaseDate, &r.PurchaseDate); err != nil {
+		errs = append(errs, ValidationError{Field: "purchaseDate", Message: err.Error()})
+	}
+
+	if err := json.Unmarshal(raw.PurchaseTime, &r.PurchaseTime); err != nil {
+		errs = append(errs, ValidationError{Field: "purchaseTime", Message: err.Error()})
+	}
+
+	if err := json.Unmarshal(raw.Total, &r.Total); err != nil {
+		errs = append(errs, ValidationError{Field: "total", Message: err.Error()})
+	}
+
+	r.Items = make([]Item, 0, len(raw.Items))
+	for i, rawItem := range raw.Items {
+		var item Item
+		if err := json.Unmarshal(rawItem, &item); err != nil {
+			errs = append(errs, ValidationError{Field: fmt.Sprintf("items[%d]", i), Message: err.Error()})
+			continue
+		}
+		r.Items = append(r.Items, item)
+	}
+
+	if len(errs) > 0 {
+		return &ValidationErrors{Errors: errs}
+	}
+	return nil
+}
+
+// MoneyAmount is a decimal value that remembers the exact string it was
+// parsed from, so marshaling reproduces the client's original formatting
+// (e.g. "6.00" stays "6.00" instead of becoming "6").
+type MoneyAmount struct {
+	Decimal decimal.Decimal
+	Raw     string
+}
+
+// NewMoneyAmount parses raw as a decimal amount.
+func NewMoneyAmount(raw string) (MoneyAmount, error) {
+	d, err := decimal.NewFromString(raw)
+	if err != nil {
+		return MoneyAmount{}, fmt.Errorf("invalid decimal amount %q", raw)
+	}
+	return MoneyAmount{Decimal: d, Raw: raw}, nil
+}
+
+func (m MoneyAmount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Raw)
+}
+
+func (m *MoneyAmount) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("must be a string")
+	}
+	amount, err := NewMoneyAmount(raw)
+	if err != nil {
+		return err
+	}
+	*m = amount
+	return nil
+}
+
+// DateString is a purchase date that remembers its original "YYYY-MM-DD"
+// string form.
+type DateString struct {
+	Time time.Time
+	Raw  string
+}
+
+// NewDateString parses raw as a "YYYY-MM-DD" date.
+func NewDateString(raw string) (DateString, error) {
+	t, err := parsePurchaseDate(raw)
+	if err != nil {
+		return DateString{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", raw)
+	}
+	return DateString{Time: t, Raw: raw}, nil
+}
+
+func (d DateString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Raw)
+}
+
+func (d *DateString) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("must be a string")
+	}
+	parsed, err := NewDateString(raw)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// TimeString is a purchase time that remembers its original "HH:MM" string
+// form.
+type TimeString struct {
+	Time time.Time
+	Raw  string
+}
+
+// NewTimeString parses raw as an "HH:MM" time.
+func NewTimeString(raw string) (TimeString, error) {
+	t, err := parseTime(raw)
+	if err != nil {
+		return TimeString{}, fmt.Errorf("invalid time %q, expected HH:MM", raw)
+	}
+	return TimeString{Time: t, Raw: raw}, nil
+}
+
+func (t TimeString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Raw)
+}
+
+func (t *TimeString) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("must be a string")
+	}
+	parsed, err := NewTimeString(raw)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
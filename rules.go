@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PurchaseWindowRule awards Points when a receipt's purchase time falls in
+// [Start, End) (24-hour "HH:MM" clock, end exclusive).
+type PurchaseWindowRule struct {
+	Start  string `toml:"start"`
+	End    string `toml:"end"`
+	Points int    `toml:"points"`
+}
+
+// RulesConfig holds every tunable constant calculatePointsBreakdown relies on, so
+// operators can adjust the scoring rules without recompiling.
+type RulesConfig struct {
+	// AlphaNumericPoints is awarded per alphanumeric character in the
+	// retailer name.
+	AlphaNumericPoints int `toml:"alphanumeric_points"`
+	// RoundDollarBonus is awarded when the total has no cents.
+	RoundDollarBonus int `toml:"round_dollar_bonus"`
+	// QuarterMultipleBonus is awarded when the total is a multiple of 0.25.
+	QuarterMultipleBonus int `toml:"quarter_multiple_bonus"`
+	// ItemPairPoints is awarded for every two items on the receipt.
+	ItemPairPoints int `toml:"item_pair_points"`
+	// ItemDescriptionMultiple is the divisor a trimmed item description's
+	// length must be a multiple of to earn a price-based bonus.
+	ItemDescriptionMultiple int `toml:"item_description_multiple"`
+	// ItemDescriptionPriceMultiplier scales the item price into points when
+	// ItemDescriptionMultiple matches.
+	ItemDescriptionPriceMultiplier float64 `toml:"item_description_price_multiplier"`
+	// OddDayPoints is awarded when the purchase date's day is odd.
+	OddDayPoints int `toml:"odd_day_points"`
+	// PurchaseWindows are evaluated against the purchase time; every window
+	// that matches contributes its Points.
+	PurchaseWindows []PurchaseWindowRule `toml:"purchase_window"`
+	// ItemDescriptionDailyCap limits how many item-description-bonus points
+	// a single retailer can accrue per 24-hour window; the window restarts
+	// at the first award that comes 24 hours or more after it began. Zero
+	// means uncapped.
+	ItemDescriptionDailyCap int `toml:"item_description_daily_cap"`
+}
+
+// defaultRules reproduces the original hardcoded scoring behavior, so the
+// server keeps working out of the box without a rules file.
+func defaultRules() RulesConfig {
+	return RulesConfig{
+		AlphaNumericPoints:             1,
+		RoundDollarBonus:               50,
+		QuarterMultipleBonus:           25,
+		ItemPairPoints:                 5,
+		ItemDescriptionMultiple:        3,
+		ItemDescriptionPriceMultiplier: 0.2,
+		OddDayPoints:                   6,
+		ItemDescriptionDailyCap:        0,
+		PurchaseWindows: []PurchaseWindowRule{
+			{Start: "14:00", End: "16:00", Points: 10},
+		},
+	}
+}
+
+// LoadRules reads and decodes the TOML rules file at path. If path does not
+// exist, it returns defaultRules() rather than an error.
+func LoadRules(path string) (RulesConfig, error) {
+	rules := defaultRules()
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return rules, nil
+	}
+
+	if _, err := toml.DecodeFile(path, &rules); err != nil {
+		return RulesConfig{}, err
+	}
+
+	if err := rules.validate(); err != nil {
+		return RulesConfig{}, err
+	}
+	return rules, nil
+}
+
+// validate rejects rule values that calculatePointsBreakdown cannot safely
+// use, such as a zero ItemDescriptionMultiple, which would divide (modulo)
+// by zero. Negative values are rejected as well.
+func (r RulesConfig) validate() error {
+	if r.ItemDescriptionMultiple <= 0 {
+		return fmt.Errorf("item_description_multiple must be positive, got %d", r.ItemDescriptionMultiple)
+	}
+	return nil
+}
+
+// parseTime parses a time in 24-hour "HH:MM" format.
+func parseTime(timeStr string) (time.Time, error) {
+	return time.Parse("15:04", timeStr)
+}
+
+// parsePurchaseDate parses a date in "YYYY-MM-DD" format.
+func parsePurchaseDate(dateStr string) (time.Time, error) {
+	return time.Parse("2006-01-02", dateStr)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrReceiptNotFound is returned by a ReceiptStore when no receipt exists for
+// the given id.
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// ReceiptStore abstracts receipt persistence so the HTTP handlers don't need
+// to know whether receipts live in memory or in a database.
+type ReceiptStore interface {
+	Save(id string, receipt Receipt) error
+	Get(id string) (Receipt, error)
+	List() (map[string]Receipt, error)
+	Delete(id string) error
+	// Ping reports whether the backing storage is reachable, used by the
+	// readiness check.
+	Ping() error
+}
+
+// MemoryStore is the default ReceiptStore: an in-memory map guarded by a
+// mutex. Receipts do not survive a restart.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	receipts map[string]Receipt
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{receipts: make(map[string]Receipt)}
+}
+
+func (s *MemoryStore) Save(id string, receipt Receipt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.receipts[id] = receipt
+	return nil
+}
+
+func (s *MemoryStore) Get(id string) (Receipt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	receipt, ok := s.receipts[id]
+	if !ok {
+		return Receipt{}, ErrReceiptNotFound
+	}
+	return receipt, nil
+}
+
+func (s *MemoryStore) List() (map[string]Receipt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]Receipt, len(s.receipts))
+	for id, receipt := range s.receipts {
+		out[id] = receipt
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.receipts[id]; !ok {
+		return ErrReceiptNotFound
+	}
+	delete(s.receipts, id)
+	return nil
+}
+
+// Ping always succeeds: the in-memory store has no external dependency to
+// lose connectivity to.
+func (s *MemoryStore) Ping() error {
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	// Drivers are registered via blank import and selected at runtime by
+	// config.Storage.Driver ("sqlite3" or "postgres").
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLStore is a ReceiptStore backed by database/sql, so the same code path
+// serves both SQLite (local/dev) and Postgres (production) depending on the
+// configured driver and DSN.
+type SQLStore struct {
+	db     *sql.DB
+	driver string
+}
+
+// NewSQLStore wraps an already-opened *sql.DB and ensures the receipts table
+// exists. driver is the same "sqlite3"/"postgres" value used to open db, and
+// picks how query placeholders are rendered.
+func NewSQLStore(db *sql.DB, driver string) (*SQLStore, error) {
+	store := &SQLStore{db: db, driver: driver}
+	if err := store.ensureSchema(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// q rewrites a query written with "?" placeholders into the form the
+// configured driver expects.
+func (s *SQLStore) q(query string) string {
+	return rewritePlaceholders(s.driver, query)
+}
+
+// rewritePlaceholders rewrites a query written with "?" placeholders into
+// the form driver expects. go-sqlite3 accepts "?" as-is; lib/pq requires
+// "$1, $2, ..." positional placeholders.
+func rewritePlaceholders(driver, query string) string {
+	if driver != "postgres" {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s *SQLStore) ensureSchema() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS receipts (
+			id            TEXT PRIMARY KEY,
+			retailer      TEXT NOT NULL,
+			purchase_date TEXT NOT NULL,
+			purchase_time TEXT NOT NULL,
+			total         TEXT NOT NULL,
+			items         TEXT NOT NULL
+		)
+	`)
+	return err
+}
+
+func (s *SQLStore) Save(id string, receipt Receipt) error {
+	itemsJSON, err := json.Marshal(receipt.Items)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.q(`
+		INSERT INTO receipts (id, retailer, purchase_date, purchase_time, total, items)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			retailer = excluded.retailer,
+			purchase_date = excluded.purchase_date,
+			purchase_time = excluded.purchase_time,
+			total = excluded.total,
+			items = excluded.items
+	`), id, receipt.Retailer, receipt.PurchaseDate.Raw, receipt.PurchaseTime.Raw, receipt.Total.Raw, itemsJSON)
+	return err
+}
+
+// scanReceipt reconstructs a Receipt from its raw column values, re-parsing
+// the typed fields the same way UnmarshalJSON would.
+func scanReceipt(retailer, purchaseDate, purchaseTime, total, itemsJSON string) (Receipt, error) {
+	receipt := Receipt{Retailer: retailer}
+
+	var err error
+	if receipt.PurchaseDate, err = NewDateString(purchaseDate); err != nil {
+		return Receipt{}, err
+	}
+	if receipt.PurchaseTime, err = NewTimeString(purchaseTime); err != nil {
+		return Receipt{}, err
+	}
+	if receipt.Total, err = NewMoneyAmount(total); err != nil {
+		return Receipt{}, err
+	}
+	if err := json.Unmarshal([]byte(itemsJSON), &receipt.Items); err != nil {
+		return Receipt{}, err
+	}
+	return receipt, nil
+}
+
+func (s *SQLStore) Get(id string) (Receipt, error) {
+	var retailer, purchaseDate, purchaseTime, total, itemsJSON string
+	row := s.db.QueryRow(s.q(`SELECT retailer, purchase_date, purchase_time, total, items FROM receipts WHERE id = ?`), id)
+	if err := row.Scan(&retailer, &purchaseDate, &purchaseTime, &total, &itemsJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return Receipt{}, ErrReceiptNotFound
+		}
+		return Receipt{}, err
+	}
+	return scanReceipt(retailer, purchaseDate, purchaseTime, total, itemsJSON)
+}
+
+func (s *SQLStore) List() (map[string]Receipt, error) {
+	rows, err := s.db.Query(`SELECT id, retailer, purchase_date, purchase_time, total, items FROM receipts`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]Receipt)
+	for rows.Next() {
+		var id, retailer, purchaseDate, purchaseTime, total, itemsJSON string
+		if err := rows.Scan(&id, &retailer, &purchaseDate, &purchaseTime, &total, &itemsJSON); err != nil {
+			return nil, err
+		}
+		receipt, err := scanReceipt(retailer, purchaseDate, purchaseTime, total, itemsJSON)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = receipt
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) Delete(id string) error {
+	res, err := s.db.Exec(s.q(`DELETE FROM receipts WHERE id = ?`), id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrReceiptNotFound
+	}
+	return nil
+}
+
+// Ping checks that the database connection is alive.
+func (s *SQLStore) Ping() error {
+	return s.db.Ping()
+}